@@ -41,8 +41,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&LowRiskOverCommitmentArgs{},
 		&NodeResourceTopologyMatchArgs{},
 		&PreemptionTolerationArgs{},
+		&RateMonotonicArgs{},
 		&TopologicalSortArgs{},
 		&NetworkOverheadArgs{},
+		&ReleaseTimeArgs{},
 	)
 	return nil
 }
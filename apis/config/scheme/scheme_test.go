@@ -36,6 +36,8 @@ import (
 	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/topologicalsort"
 	"sigs.k8s.io/scheduler-plugins/pkg/noderesources"
 	"sigs.k8s.io/scheduler-plugins/pkg/preemptiontoleration"
+	"sigs.k8s.io/scheduler-plugins/pkg/rtpreemptive/ratemonotonic"
+	"sigs.k8s.io/scheduler-plugins/pkg/rtpreemptive/releasetime"
 	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/loadvariationriskbalancing"
 	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/lowriskovercommitment"
 	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/targetloadpacking"
@@ -98,6 +100,13 @@ profiles:
     args:
       minCandidateNodesPercentage: 20
       minCandidateNodesAbsolute: 200
+  - name: RateMonotonic
+    args:
+      minCandidateNodesPercentage: 20
+      minCandidateNodesAbsolute: 200
+  - name: ReleaseTime
+    args:
+      strictValidation: true
 `),
 			wantProfiles: []schedconfig.KubeSchedulerProfile{
 				{
@@ -155,6 +164,14 @@ profiles:
 							Name: preemptiontoleration.Name,
 							Args: &config.PreemptionTolerationArgs{MinCandidateNodesPercentage: 20, MinCandidateNodesAbsolute: 200},
 						},
+						{
+							Name: ratemonotonic.Name,
+							Args: &config.RateMonotonicArgs{MinCandidateNodesPercentage: 20, MinCandidateNodesAbsolute: 200},
+						},
+						{
+							Name: releasetime.Name,
+							Args: &config.ReleaseTimeArgs{StrictValidation: true},
+						},
 						{
 							Name: "DefaultPreemption",
 							Args: &schedconfig.DefaultPreemptionArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
@@ -210,6 +227,10 @@ profiles:
     args:
   - name: PreemptionToleration
     args:
+  - name: RateMonotonic
+    args:
+  - name: ReleaseTime
+    args:
 `),
 			wantProfiles: []schedconfig.KubeSchedulerProfile{
 				{
@@ -267,6 +288,14 @@ profiles:
 							Name: preemptiontoleration.Name,
 							Args: &config.PreemptionTolerationArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
 						},
+						{
+							Name: ratemonotonic.Name,
+							Args: &config.RateMonotonicArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
+						},
+						{
+							Name: releasetime.Name,
+							Args: &config.ReleaseTimeArgs{},
+						},
 						{
 							Name: "DefaultPreemption",
 							Args: &schedconfig.DefaultPreemptionArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
@@ -483,6 +512,13 @@ profiles:
       - "networkAware"
       weightsName: "netCosts"
       networkTopologyName: "net-topology-v1"
+  - name: RateMonotonic
+    args:
+      minCandidateNodesPercentage: 20
+      minCandidateNodesAbsolute: 200
+  - name: ReleaseTime
+    args:
+      strictValidation: true
 `),
 			wantProfiles: []schedconfig.KubeSchedulerProfile{
 				{
@@ -509,6 +545,14 @@ profiles:
 								NetworkTopologyName: "net-topology-v1",
 							},
 						},
+						{
+							Name: ratemonotonic.Name,
+							Args: &config.RateMonotonicArgs{MinCandidateNodesPercentage: 20, MinCandidateNodesAbsolute: 200},
+						},
+						{
+							Name: releasetime.Name,
+							Args: &config.ReleaseTimeArgs{StrictValidation: true},
+						},
 						{
 							Name: "DefaultPreemption",
 							Args: &schedconfig.DefaultPreemptionArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
@@ -558,6 +602,10 @@ profiles:
     args:
   - name: NetworkOverhead
     args:
+  - name: RateMonotonic
+    args:
+  - name: ReleaseTime
+    args:
 `),
 			wantProfiles: []schedconfig.KubeSchedulerProfile{
 				{
@@ -578,6 +626,14 @@ profiles:
 								NetworkTopologyName: "nt-default",
 							},
 						},
+						{
+							Name: ratemonotonic.Name,
+							Args: &config.RateMonotonicArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
+						},
+						{
+							Name: releasetime.Name,
+							Args: &config.ReleaseTimeArgs{},
+						},
 						{
 							Name: "DefaultPreemption",
 							Args: &schedconfig.DefaultPreemptionArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
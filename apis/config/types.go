@@ -219,6 +219,11 @@ type PreemptionTolerationArgs schedconfig.DefaultPreemptionArgs
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RateMonotonicArgs reuses DefaultPluginArgs.
+type RateMonotonicArgs schedconfig.DefaultPreemptionArgs
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type TopologicalSortArgs struct {
 	metav1.TypeMeta
 
@@ -240,3 +245,15 @@ type NetworkOverheadArgs struct {
 	// The NetworkTopology CRD name
 	NetworkTopologyName string
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReleaseTimeArgs holds arguments used to configure the ReleaseTime plugin.
+type ReleaseTimeArgs struct {
+	metav1.TypeMeta
+
+	// StrictValidation, if true, keeps pods with malformed rt-preemptive annotations
+	// out of the scheduling queue. Otherwise the malformed annotations are ignored.
+	// Either way, a warning Event listing them is recorded on the pod.
+	StrictValidation bool
+}
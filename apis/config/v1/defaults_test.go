@@ -216,6 +216,24 @@ func TestSchedulingDefaults(t *testing.T) {
 				MinCandidateNodesAbsolute:   pointer.Int32Ptr(100),
 			},
 		},
+		{
+			name:   "empty config RateMonotonicArgs",
+			config: &RateMonotonicArgs{},
+			expect: &RateMonotonicArgs{
+				MinCandidateNodesPercentage: pointer.Int32Ptr(10),
+				MinCandidateNodesAbsolute:   pointer.Int32Ptr(100),
+			},
+		},
+		{
+			name: "set non default RateMonotonicArgs",
+			config: &RateMonotonicArgs{
+				MinCandidateNodesPercentage: pointer.Int32Ptr(20),
+			},
+			expect: &RateMonotonicArgs{
+				MinCandidateNodesPercentage: pointer.Int32Ptr(20),
+				MinCandidateNodesAbsolute:   pointer.Int32Ptr(100),
+			},
+		},
 		{
 			name:   "empty config TopologySortArgs",
 			config: &TopologicalSortArgs{},
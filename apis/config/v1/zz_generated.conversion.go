@@ -120,6 +120,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RateMonotonicArgs)(nil), (*config.RateMonotonicArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_RateMonotonicArgs_To_config_RateMonotonicArgs(a.(*RateMonotonicArgs), b.(*config.RateMonotonicArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RateMonotonicArgs)(nil), (*RateMonotonicArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RateMonotonicArgs_To_v1_RateMonotonicArgs(a.(*config.RateMonotonicArgs), b.(*RateMonotonicArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ReleaseTimeArgs)(nil), (*config.ReleaseTimeArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ReleaseTimeArgs_To_config_ReleaseTimeArgs(a.(*ReleaseTimeArgs), b.(*config.ReleaseTimeArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ReleaseTimeArgs)(nil), (*ReleaseTimeArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ReleaseTimeArgs_To_v1_ReleaseTimeArgs(a.(*config.ReleaseTimeArgs), b.(*ReleaseTimeArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ScoringStrategy)(nil), (*config.ScoringStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ScoringStrategy_To_config_ScoringStrategy(a.(*ScoringStrategy), b.(*config.ScoringStrategy), scope)
 	}); err != nil {
@@ -435,6 +455,56 @@ func Convert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in *
 	return autoConvert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in, out, s)
 }
 
+func autoConvert_v1_RateMonotonicArgs_To_config_RateMonotonicArgs(in *RateMonotonicArgs, out *config.RateMonotonicArgs, s conversion.Scope) error {
+	if err := metav1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_RateMonotonicArgs_To_config_RateMonotonicArgs is an autogenerated conversion function.
+func Convert_v1_RateMonotonicArgs_To_config_RateMonotonicArgs(in *RateMonotonicArgs, out *config.RateMonotonicArgs, s conversion.Scope) error {
+	return autoConvert_v1_RateMonotonicArgs_To_config_RateMonotonicArgs(in, out, s)
+}
+
+func autoConvert_config_RateMonotonicArgs_To_v1_RateMonotonicArgs(in *config.RateMonotonicArgs, out *RateMonotonicArgs, s conversion.Scope) error {
+	if err := metav1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int32_To_Pointer_int32(&in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_RateMonotonicArgs_To_v1_RateMonotonicArgs is an autogenerated conversion function.
+func Convert_config_RateMonotonicArgs_To_v1_RateMonotonicArgs(in *config.RateMonotonicArgs, out *RateMonotonicArgs, s conversion.Scope) error {
+	return autoConvert_config_RateMonotonicArgs_To_v1_RateMonotonicArgs(in, out, s)
+}
+
+func autoConvert_v1_ReleaseTimeArgs_To_config_ReleaseTimeArgs(in *ReleaseTimeArgs, out *config.ReleaseTimeArgs, s conversion.Scope) error {
+	out.StrictValidation = in.StrictValidation
+	return nil
+}
+
+// Convert_v1_ReleaseTimeArgs_To_config_ReleaseTimeArgs is an autogenerated conversion function.
+func Convert_v1_ReleaseTimeArgs_To_config_ReleaseTimeArgs(in *ReleaseTimeArgs, out *config.ReleaseTimeArgs, s conversion.Scope) error {
+	return autoConvert_v1_ReleaseTimeArgs_To_config_ReleaseTimeArgs(in, out, s)
+}
+
+func autoConvert_config_ReleaseTimeArgs_To_v1_ReleaseTimeArgs(in *config.ReleaseTimeArgs, out *ReleaseTimeArgs, s conversion.Scope) error {
+	out.StrictValidation = in.StrictValidation
+	return nil
+}
+
+// Convert_config_ReleaseTimeArgs_To_v1_ReleaseTimeArgs is an autogenerated conversion function.
+func Convert_config_ReleaseTimeArgs_To_v1_ReleaseTimeArgs(in *config.ReleaseTimeArgs, out *ReleaseTimeArgs, s conversion.Scope) error {
+	return autoConvert_config_ReleaseTimeArgs_To_v1_ReleaseTimeArgs(in, out, s)
+}
+
 func autoConvert_v1_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrategy, out *config.ScoringStrategy, s conversion.Scope) error {
 	out.Type = config.ScoringStrategyType(in.Type)
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
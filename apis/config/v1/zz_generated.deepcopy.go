@@ -338,6 +338,66 @@ func (in *PreemptionTolerationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateMonotonicArgs) DeepCopyInto(out *RateMonotonicArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.MinCandidateNodesPercentage != nil {
+		in, out := &in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinCandidateNodesAbsolute != nil {
+		in, out := &in.MinCandidateNodesAbsolute, &out.MinCandidateNodesAbsolute
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateMonotonicArgs.
+func (in *RateMonotonicArgs) DeepCopy() *RateMonotonicArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(RateMonotonicArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RateMonotonicArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseTimeArgs) DeepCopyInto(out *ReleaseTimeArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseTimeArgs.
+func (in *ReleaseTimeArgs) DeepCopy() *ReleaseTimeArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseTimeArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReleaseTimeArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
@@ -42,6 +42,7 @@ func RegisterDefaults(scheme *runtime.Scheme) error {
 		SetObjectDefaults_NodeResourcesAllocatableArgs(obj.(*NodeResourcesAllocatableArgs))
 	})
 	scheme.AddTypeDefaultingFunc(&PreemptionTolerationArgs{}, func(obj interface{}) { SetObjectDefaults_PreemptionTolerationArgs(obj.(*PreemptionTolerationArgs)) })
+	scheme.AddTypeDefaultingFunc(&RateMonotonicArgs{}, func(obj interface{}) { SetObjectDefaults_RateMonotonicArgs(obj.(*RateMonotonicArgs)) })
 	scheme.AddTypeDefaultingFunc(&TargetLoadPackingArgs{}, func(obj interface{}) { SetObjectDefaults_TargetLoadPackingArgs(obj.(*TargetLoadPackingArgs)) })
 	scheme.AddTypeDefaultingFunc(&TopologicalSortArgs{}, func(obj interface{}) { SetObjectDefaults_TopologicalSortArgs(obj.(*TopologicalSortArgs)) })
 	return nil
@@ -75,6 +76,10 @@ func SetObjectDefaults_PreemptionTolerationArgs(in *PreemptionTolerationArgs) {
 	SetDefaults_PreemptionTolerationArgs(in)
 }
 
+func SetObjectDefaults_RateMonotonicArgs(in *RateMonotonicArgs) {
+	SetDefaults_RateMonotonicArgs(in)
+}
+
 func SetObjectDefaults_TargetLoadPackingArgs(in *TargetLoadPackingArgs) {
 	SetDefaults_TargetLoadPackingArgs(in)
 }
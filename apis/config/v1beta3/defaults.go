@@ -207,6 +207,11 @@ func SetDefaults_PreemptionTolerationArgs(obj *PreemptionTolerationArgs) {
 	k8sschedulerconfigv1beta3.SetDefaults_DefaultPreemptionArgs((*schedulerconfigv1beta3.DefaultPreemptionArgs)(obj))
 }
 
+// SetDefaults_RateMonotonicArgs reuses SetDefaults_DefaultPreemptionArgs
+func SetDefaults_RateMonotonicArgs(obj *RateMonotonicArgs) {
+	k8sschedulerconfigv1beta3.SetDefaults_DefaultPreemptionArgs((*schedulerconfigv1beta3.DefaultPreemptionArgs)(obj))
+}
+
 // SetDefaults_TopologicalSortArgs sets the default parameters for TopologicalSortArgs plugin.
 func SetDefaults_TopologicalSortArgs(obj *TopologicalSortArgs) {
 	if len(obj.Namespaces) == 0 {
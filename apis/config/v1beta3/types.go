@@ -220,6 +220,11 @@ type PreemptionTolerationArgs schedulerconfigv1beta3.DefaultPreemptionArgs
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// RateMonotonicArgs reuses DefaultPluginArgs.
+type RateMonotonicArgs schedulerconfigv1beta3.DefaultPreemptionArgs
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type TopologicalSortArgs struct {
 	metav1.TypeMeta `json:",inline"`
 
@@ -241,3 +246,15 @@ type NetworkOverheadArgs struct {
 	// The NetworkTopology CRD name
 	NetworkTopologyName *string `json:"networkTopologyName,omitempty"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReleaseTimeArgs holds arguments used to configure the ReleaseTime plugin.
+type ReleaseTimeArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// StrictValidation, if true, keeps pods with malformed rt-preemptive annotations
+	// out of the scheduling queue. Otherwise the malformed annotations are ignored.
+	// Either way, a warning Event listing them is recorded on the pod.
+	StrictValidation bool `json:"strictValidation,omitempty"`
+}
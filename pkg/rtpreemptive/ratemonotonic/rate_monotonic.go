@@ -0,0 +1,501 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratemonotonic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	schedulerapisconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/pkg/scheduler/util"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+const (
+	// Name of the plugin used in the plugin registry and configurations.
+	Name = "RateMonotonic"
+
+	// preFilterStateKey is the key in CycleState to RateMonotonic pre-computed data.
+	preFilterStateKey = "PreFilter" + Name
+
+	// ErrReasonUtilizationBound is the reason for a node failing the utilization admission limit.
+	ErrReasonUtilizationBound = "node(s) exceeded the rate-monotonic utilization limit"
+
+	// defaultMilliCPURequest is the CPU demand assumed for periodic pods that don't
+	// request CPU, i.e., one full core while they are running.
+	defaultMilliCPURequest int64 = 1000
+)
+
+var (
+	_ framework.QueueSortPlugin  = &RateMonotonic{}
+	_ framework.PreFilterPlugin  = &RateMonotonic{}
+	_ framework.FilterPlugin     = &RateMonotonic{}
+	_ framework.PostFilterPlugin = &RateMonotonic{}
+	_ preemption.Interface       = &RateMonotonic{}
+)
+
+// RateMonotonic is a plugin that schedules periodic pods following the
+// rate-monotonic policy: the shorter the period, the higher the priority.
+type RateMonotonic struct {
+	fh        framework.Handle
+	args      config.RateMonotonicArgs
+	podLister corelisters.PodLister
+	pdbLister policylisters.PodDisruptionBudgetLister
+	tasks     *taskCache
+}
+
+// preFilterState computed at PreFilter and used at Filter.
+type preFilterState struct {
+	// task is nil if the pod is not periodic.
+	task *Task
+	// milliCPU is the CPU request of the pod in millicores.
+	milliCPU int64
+}
+
+// Clone the preFilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (rm *RateMonotonic) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.RateMonotonicArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type RateMonotonicArgs, got %T", obj)
+	}
+
+	if err := validation.ValidateDefaultPreemptionArgs(field.NewPath(""), (*schedulerapisconfig.DefaultPreemptionArgs)(args)); err != nil {
+		return nil, err
+	}
+
+	rm := RateMonotonic{
+		fh:        fh,
+		args:      *args,
+		podLister: fh.SharedInformerFactory().Core().V1().Pods().Lister(),
+		pdbLister: getPDBLister(fh.SharedInformerFactory()),
+		tasks:     newTaskCache(),
+	}
+	fh.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: rm.tasks.deletePod,
+	})
+	return &rm, nil
+}
+
+// Less is the function used by the activeQ heap algorithm to sort pods.
+// It sorts pods based on their priorities. When the priorities are equal, periodic
+// pods come before non-periodic ones and shorter periods come first. Remaining
+// ties are broken by the timestamp the pods were added to the queue.
+func (rm *RateMonotonic) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	if c := rm.compareRank(pInfo1.Pod, pInfo2.Pod); c != 0 {
+		return c < 0
+	}
+	return pInfo1.Timestamp.Before(pInfo2.Timestamp)
+}
+
+// PreFilter invoked at the prefilter extension point.
+// It parses the rate-monotonic annotations of the pod once per scheduling cycle and
+// rejects pods with invalid annotations.
+func (rm *RateMonotonic) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	task, err := parseTask(pod)
+	if err != nil {
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+	state.Write(preFilterStateKey, &preFilterState{task: task, milliCPU: podMilliCPURequest(pod)})
+	return nil, nil
+}
+
+// PreFilterExtensions returns nil as RateMonotonic doesn't keep pod-dependent state.
+func (rm *RateMonotonic) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter invoked at the filter extension point.
+// It applies a heuristic admission limit to the periodic pods on the node, including the
+// incoming one: each pod's utilization exec-time/period is weighted by its CPU request,
+// and the sum must stay within the CPU allocatable of the node scaled by the Liu & Layland
+// factor n(2^(1/n) - 1), i.e., Σ U_i * cpu_i <= n(2^(1/n) - 1) * allocatable. n counts the
+// pods across all cores, and the pods are neither partitioned to cores nor run
+// rate-monotonically by the node, so this is not a schedulability test and passing it
+// doesn't guarantee deadlines. Only pods annotated with both a period and an execution
+// time take part in the check.
+func (rm *RateMonotonic) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	task := s.task
+	if task == nil || task.ExecTime == 0 {
+		return nil
+	}
+
+	n, demand := 1, task.Utilization()*float64(s.milliCPU)
+	for _, pi := range nodeInfo.Pods {
+		t := rm.tasks.get(pi.Pod)
+		if t == nil || t.ExecTime == 0 {
+			continue
+		}
+		n++
+		demand += t.Utilization() * float64(podMilliCPURequest(pi.Pod))
+	}
+
+	if capacity := utilizationBound(n) * float64(nodeInfo.Allocatable.MilliCPU); demand > capacity {
+		klog.V(5).InfoS("Node exceeded the rate-monotonic utilization limit", "pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.Node()), "demandMilliCPU", demand, "capacityMilliCPU", capacity)
+		return framework.NewStatus(framework.Unschedulable, ErrReasonUtilizationBound)
+	}
+	return nil
+}
+
+// PostFilter invoked at the postFilter extension point.
+func (rm *RateMonotonic) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	defer func() {
+		metrics.PreemptionAttempts.Inc()
+	}()
+
+	pe := preemption.Evaluator{
+		PluginName: rm.Name(),
+		Handler:    rm.fh,
+		PodLister:  rm.podLister,
+		PdbLister:  rm.pdbLister,
+		State:      state,
+		Interface:  rm,
+	}
+	return pe.Preempt(ctx, pod, m)
+}
+
+// SelectVictimsOnNode finds minimum set of pods on the given node that should
+// be preempted in order to make enough room for "preemptor" to be scheduled.
+// The algorithm is almost identical to DefaultPreemption plugin's one.
+// The only difference is that a periodic pod of the same priority can be
+// preempted when it has a strictly longer period than the preemptor.
+func (rm *RateMonotonic) SelectVictimsOnNode(
+	ctx context.Context,
+	state *framework.CycleState,
+	preemptor *v1.Pod,
+	nodeInfo *framework.NodeInfo,
+	pdbs []*policy.PodDisruptionBudget) ([]*v1.Pod, int, *framework.Status) {
+	var potentialVictims []*framework.PodInfo
+	removePod := func(rpi *framework.PodInfo) error {
+		if err := nodeInfo.RemovePod(rpi.Pod); err != nil {
+			return err
+		}
+		status := rm.fh.RunPreFilterExtensionRemovePod(ctx, state, preemptor, rpi, nodeInfo)
+		if !status.IsSuccess() {
+			return status.AsError()
+		}
+		return nil
+	}
+	addPod := func(api *framework.PodInfo) error {
+		nodeInfo.AddPodInfo(api)
+		status := rm.fh.RunPreFilterExtensionAddPod(ctx, state, preemptor, api, nodeInfo)
+		if !status.IsSuccess() {
+			return status.AsError()
+		}
+		return nil
+	}
+
+	// As the first step, remove all pods the preemptor can preempt from the node
+	// and check if the given pod can be scheduled.
+	for _, pi := range nodeInfo.Pods {
+		if !rm.canPreempt(preemptor, pi.Pod) {
+			continue
+		}
+		potentialVictims = append(potentialVictims, pi)
+		if err := removePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+	}
+
+	// No potential victims are found, and so we don't need to evaluate the node again since its state didn't change.
+	if len(potentialVictims) == 0 {
+		message := fmt.Sprintf("No victims found on node %v for preemptor pod %v", nodeInfo.Node().Name, preemptor.Name)
+		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, message)
+	}
+
+	// If the new pod does not fit after removing all the preemptable pods,
+	// we are almost done and this node is not suitable for preemption.
+	if status := rm.fh.RunFilterPluginsWithNominatedPods(ctx, state, preemptor, nodeInfo); !status.IsSuccess() {
+		return nil, 0, status
+	}
+	var victims []*v1.Pod
+	numViolatingVictim := 0
+	sort.Slice(potentialVictims, func(i, j int) bool { return rm.moreImportantPod(potentialVictims[i].Pod, potentialVictims[j].Pod) })
+	// Try to reprieve as many pods as possible. We first try to reprieve the PDB
+	// violating victims and then other non-violating ones. In both cases, we start
+	// from the highest ranked victims.
+	violatingVictims, nonViolatingVictims := filterPodsWithPDBViolation(potentialVictims, pdbs)
+	reprievePod := func(pi *framework.PodInfo) (bool, error) {
+		if err := addPod(pi); err != nil {
+			return false, err
+		}
+		status := rm.fh.RunFilterPluginsWithNominatedPods(ctx, state, preemptor, nodeInfo)
+		fits := status.IsSuccess()
+		if !fits {
+			if err := removePod(pi); err != nil {
+				return false, err
+			}
+			rpi := pi.Pod
+			victims = append(victims, rpi)
+			klog.V(5).InfoS("Pod is a potential preemption victim on node", "pod", klog.KObj(rpi), "node", klog.KObj(nodeInfo.Node()))
+		}
+		return fits, nil
+	}
+	for _, p := range violatingVictims {
+		if fits, err := reprievePod(p); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		} else if !fits {
+			numViolatingVictim++
+		}
+	}
+	// Now we try to reprieve non-violating victims.
+	for _, p := range nonViolatingVictims {
+		if _, err := reprievePod(p); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+	}
+	return victims, numViolatingVictim, framework.NewStatus(framework.Success)
+}
+
+// PodEligibleToPreemptOthers determines whether this pod should be considered
+// for preempting other pods or not. If this pod has already preempted other
+// pods and those are in their graceful termination period, it shouldn't be
+// considered for preemption.
+// We look at the node that is nominated for this pod and as long as there are
+// terminating pods it can preempt on the node, we don't consider this for preempting more pods.
+func (rm *RateMonotonic) PodEligibleToPreemptOthers(pod *v1.Pod, nominatedNodeStatus *framework.Status) (bool, string) {
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever {
+		klog.V(5).InfoS("Pod is not eligible for preemption because it has a preemptionPolicy of Never", "pod", klog.KObj(pod))
+		return false, "not eligible due to preemptionPolicy=Never."
+	}
+	nodeInfos := rm.fh.SnapshotSharedLister().NodeInfos()
+	nomNodeName := pod.Status.NominatedNodeName
+	if len(nomNodeName) > 0 {
+		// If the pod's nominated node is considered as UnschedulableAndUnresolvable by the filters,
+		// then the pod should be considered for preempting again.
+		if nominatedNodeStatus.Code() == framework.UnschedulableAndUnresolvable {
+			return true, ""
+		}
+
+		if nodeInfo, _ := nodeInfos.Get(nomNodeName); nodeInfo != nil {
+			for _, p := range nodeInfo.Pods {
+				if p.Pod.DeletionTimestamp != nil && rm.canPreempt(pod, p.Pod) {
+					return false, "not eligible due to a terminating pod on the nominated node."
+				}
+			}
+		}
+	}
+	return true, ""
+}
+
+// canPreempt returns true if the preemptor is allowed to preempt the victim. As with
+// DefaultPreemption, pods of a lower priority can always be preempted. Pods of the same
+// priority can only be preempted when both are periodic and the victim's period is
+// strictly longer, so that annotating a period doesn't allow evicting non-periodic pods.
+func (rm *RateMonotonic) canPreempt(preemptor, victim *v1.Pod) bool {
+	prio1, prio2 := corev1helpers.PodPriority(preemptor), corev1helpers.PodPriority(victim)
+	if prio1 != prio2 {
+		return prio1 > prio2
+	}
+	t1, t2 := rm.tasks.get(preemptor), rm.tasks.get(victim)
+	return t1 != nil && t2 != nil && t1.Period < t2.Period
+}
+
+// compareRank compares two pods by priority first and then by their rate-monotonic
+// rank. It returns a negative value if p1 ranks above p2, a positive value if p2
+// ranks above p1, and zero if neither does.
+func (rm *RateMonotonic) compareRank(p1, p2 *v1.Pod) int {
+	prio1, prio2 := corev1helpers.PodPriority(p1), corev1helpers.PodPriority(p2)
+	if prio1 != prio2 {
+		if prio1 > prio2 {
+			return -1
+		}
+		return 1
+	}
+	return rm.comparePeriod(p1, p2)
+}
+
+// comparePeriod compares two pods by their periods. Periodic pods rank above
+// non-periodic ones, and pods with invalid annotations are treated as non-periodic.
+func (rm *RateMonotonic) comparePeriod(p1, p2 *v1.Pod) int {
+	t1, t2 := rm.tasks.get(p1), rm.tasks.get(p2)
+	switch {
+	case t1 == nil && t2 == nil:
+		return 0
+	case t2 == nil:
+		return -1
+	case t1 == nil:
+		return 1
+	case t1.Period < t2.Period:
+		return -1
+	case t1.Period > t2.Period:
+		return 1
+	}
+	return 0
+}
+
+// moreImportantPod returns true when p1 ranks above p2, falling back to
+// util.MoreImportantPod when their rate-monotonic ranks are equal.
+func (rm *RateMonotonic) moreImportantPod(p1, p2 *v1.Pod) bool {
+	if c := rm.compareRank(p1, p2); c != 0 {
+		return c < 0
+	}
+	return util.MoreImportantPod(p1, p2)
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("error reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to RateMonotonic.preFilterState error", c)
+	}
+	return s, nil
+}
+
+// podMilliCPURequest returns the CPU request of the pod in millicores, or
+// defaultMilliCPURequest if the pod doesn't request CPU.
+func podMilliCPURequest(pod *v1.Pod) int64 {
+	reqs := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	if cpu, ok := reqs[v1.ResourceCPU]; ok && !cpu.IsZero() {
+		return cpu.MilliValue()
+	}
+	return defaultMilliCPURequest
+}
+
+// utilizationBound returns the Liu & Layland utilization bound for n tasks on a single
+// processor, which Filter uses as the scaling factor of its admission limit.
+func utilizationBound(n int) float64 {
+	return float64(n) * (math.Pow(2, 1/float64(n)) - 1)
+}
+
+/* DO NOT EDIT CONTENT BELOW */
+/* Copied from k/k#pkg/scheduler/framework/plugins/defaultpreemption/default_preemption.go */
+
+// GetOffsetAndNumCandidates chooses a random offset and calculates the number
+// of candidates that should be shortlisted for dry running preemption.
+func (rm *RateMonotonic) GetOffsetAndNumCandidates(numNodes int32) (int32, int32) {
+	return rand.Int31n(numNodes), rm.calculateNumCandidates(numNodes)
+}
+
+func (rm *RateMonotonic) CandidatesToVictimsMap(candidates []preemption.Candidate) map[string]*extenderv1.Victims {
+	m := make(map[string]*extenderv1.Victims)
+	for _, c := range candidates {
+		m[c.Name()] = c.Victims()
+	}
+	return m
+}
+
+// calculateNumCandidates returns the number of candidates the FindCandidates
+// method must produce from dry running based on the constraints given by
+// <minCandidateNodesPercentage> and <minCandidateNodesAbsolute>. The number of
+// candidates returned will never be greater than <numNodes>.
+func (rm *RateMonotonic) calculateNumCandidates(numNodes int32) int32 {
+	n := (numNodes * rm.args.MinCandidateNodesPercentage) / 100
+	if n < rm.args.MinCandidateNodesAbsolute {
+		n = rm.args.MinCandidateNodesAbsolute
+	}
+	if n > numNodes {
+		n = numNodes
+	}
+	return n
+}
+
+// filterPodsWithPDBViolation groups the given "pods" into two groups of "violatingPods"
+// and "nonViolatingPods" based on whether their PDBs will be violated if they are
+// preempted.
+// This function is stable and does not change the order of received pods. So, if it
+// receives a sorted list, grouping will preserve the order of the input list.
+func filterPodsWithPDBViolation(podInfos []*framework.PodInfo, pdbs []*policy.PodDisruptionBudget) (violatingPodInfos, nonViolatingPodInfos []*framework.PodInfo) {
+	pdbsAllowed := make([]int32, len(pdbs))
+	for i, pdb := range pdbs {
+		pdbsAllowed[i] = pdb.Status.DisruptionsAllowed
+	}
+
+	for _, podInfo := range podInfos {
+		pod := podInfo.Pod
+		pdbForPodIsViolated := false
+		// A pod with no labels will not match any PDB. So, no need to check.
+		if len(pod.Labels) != 0 {
+			for i, pdb := range pdbs {
+				if pdb.Namespace != pod.Namespace {
+					continue
+				}
+				selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				if err != nil {
+					continue
+				}
+				// A PDB with a nil or empty selector matches nothing.
+				if selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+					continue
+				}
+
+				// Existing in DisruptedPods means it has been processed in API server,
+				// we don't treat it as a violating case.
+				if _, exist := pdb.Status.DisruptedPods[pod.Name]; exist {
+					continue
+				}
+				// Only decrement the matched pdb when it's not in its <DisruptedPods>;
+				// otherwise we may over-decrement the budget number.
+				pdbsAllowed[i]--
+				// We have found a matching PDB.
+				if pdbsAllowed[i] < 0 {
+					pdbForPodIsViolated = true
+				}
+			}
+		}
+		if pdbForPodIsViolated {
+			violatingPodInfos = append(violatingPodInfos, podInfo)
+		} else {
+			nonViolatingPodInfos = append(nonViolatingPodInfos, podInfo)
+		}
+	}
+	return violatingPodInfos, nonViolatingPodInfos
+}
+
+func getPDBLister(informerFactory informers.SharedInformerFactory) policylisters.PodDisruptionBudgetLister {
+	return informerFactory.Policy().V1().PodDisruptionBudgets().Lister()
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratemonotonic
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// AnnotationKeyPrefix is the prefix shared by all annotations of the rtpreemptive plugins.
+	AnnotationKeyPrefix = "rt-preemptive.scheduling.x-k8s.io/"
+	// AnnotationKeyPeriod is the annotation key of a pod's period, as a Go duration string.
+	AnnotationKeyPeriod = AnnotationKeyPrefix + "period"
+	// AnnotationKeyExecTime is the annotation key of a pod's execution time within one period,
+	// as a Go duration string.
+	AnnotationKeyExecTime = AnnotationKeyPrefix + "exec-time"
+)
+
+// Task holds the periodic task model of a pod. Each property value is annotated in the pod.
+// Example:
+//
+//	kind: Pod
+//	  metadata:
+//	  name: periodic-sample
+//	  annotations:
+//	    rt-preemptive.scheduling.x-k8s.io/period: "100ms"
+//	    rt-preemptive.scheduling.x-k8s.io/exec-time: "20ms"
+type Task struct {
+	// Period specifies the release interval of the task. Under rate-monotonic
+	// scheduling, a shorter period means a higher priority.
+	Period time.Duration
+
+	// ExecTime specifies the execution time of the task within one period.
+	// It is zero if not set, in which case the task doesn't take part in the
+	// utilization admission check.
+	ExecTime time.Duration
+}
+
+// Utilization returns the fraction of one processor the task needs, i.e., ExecTime/Period.
+func (t *Task) Utilization() float64 {
+	return float64(t.ExecTime) / float64(t.Period)
+}
+
+// parseTask returns the periodic task model of the given pod, or nil if the pod
+// is not annotated with a period.
+func parseTask(pod *v1.Pod) (*Task, error) {
+	periodStr, ok := pod.Annotations[AnnotationKeyPeriod]
+	if !ok {
+		return nil, nil
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil {
+		return nil, err
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("annotation %s must be positive, got %q", AnnotationKeyPeriod, periodStr)
+	}
+	task := &Task{Period: period}
+
+	execTimeStr, ok := pod.Annotations[AnnotationKeyExecTime]
+	if !ok {
+		return task, nil
+	}
+	execTime, err := time.ParseDuration(execTimeStr)
+	if err != nil {
+		return nil, err
+	}
+	if execTime < 0 || execTime > period {
+		return nil, fmt.Errorf("annotation %s must be within [0, %s], got %q", AnnotationKeyExecTime, period, execTimeStr)
+	}
+	task.ExecTime = execTime
+	return task, nil
+}
+
+// taskCache memoizes the periodic task model of pods by UID, so that the queue sort
+// and the filter don't parse the annotations of the same pods over and over. An entry
+// is reparsed when the pod's annotation values differ from the cached ones.
+type taskCache struct {
+	sync.RWMutex
+	items map[types.UID]*taskCacheItem
+}
+
+type taskCacheItem struct {
+	period   string
+	execTime string
+	// task is nil for non-periodic pods and for pods with invalid annotations.
+	task *Task
+}
+
+func newTaskCache() *taskCache {
+	return &taskCache{items: make(map[types.UID]*taskCacheItem)}
+}
+
+// get returns the periodic task model of the given pod. Pods with invalid
+// annotations are treated as non-periodic; they are rejected at PreFilter.
+func (c *taskCache) get(pod *v1.Pod) *Task {
+	period, execTime := pod.Annotations[AnnotationKeyPeriod], pod.Annotations[AnnotationKeyExecTime]
+	c.RLock()
+	item, ok := c.items[pod.UID]
+	c.RUnlock()
+	if ok && item.period == period && item.execTime == execTime {
+		return item.task
+	}
+
+	task, err := parseTask(pod)
+	if err != nil {
+		klog.V(5).InfoS("Treating pod with invalid rate-monotonic annotations as non-periodic", "pod", klog.KObj(pod), "err", err)
+		task = nil
+	}
+	c.Lock()
+	c.items[pod.UID] = &taskCacheItem{period: period, execTime: execTime, task: task}
+	c.Unlock()
+	return task
+}
+
+// deletePod removes the cached task of a deleted pod.
+func (c *taskCache) deletePod(obj interface{}) {
+	var pod *v1.Pod
+	switch t := obj.(type) {
+	case *v1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		if pod, ok = t.Obj.(*v1.Pod); !ok {
+			return
+		}
+	default:
+		return
+	}
+	c.Lock()
+	delete(c.items, pod.UID)
+	c.Unlock()
+}
@@ -0,0 +1,651 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratemonotonic
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makePeriodicPod(name string, priority int32, period, execTime string) *v1.Pod {
+	pw := st.MakePod().Name(name).Namespace("default").UID(name).Priority(priority)
+	if period != "" {
+		pw = pw.Annotation(AnnotationKeyPeriod, period)
+	}
+	if execTime != "" {
+		pw = pw.Annotation(AnnotationKeyExecTime, execTime)
+	}
+	return pw.Obj()
+}
+
+func createQueuedPodInfo(pod *v1.Pod, ts time.Time) *framework.QueuedPodInfo {
+	podInfo, _ := framework.NewPodInfo(pod)
+	return &framework.QueuedPodInfo{PodInfo: podInfo, Timestamp: ts}
+}
+
+func TestLess(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		pInfo1 *framework.QueuedPodInfo
+		pInfo2 *framework.QueuedPodInfo
+		want   bool
+	}{
+		{
+			name:   "p1's priority greater than p2 with a longer period",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 100, "1s", ""), now),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 50, "100ms", ""), now),
+			want:   true,
+		},
+		{
+			name:   "same priority, p1's period shorter than p2",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 0, "100ms", ""), now.Add(time.Second)),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 0, "1s", ""), now),
+			want:   true,
+		},
+		{
+			name:   "same priority, p1's period longer than p2",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 0, "1s", ""), now),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 0, "100ms", ""), now.Add(time.Second)),
+			want:   false,
+		},
+		{
+			name:   "same priority, only p2 is periodic",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 0, "", ""), now),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 0, "1h", ""), now.Add(time.Second)),
+			want:   false,
+		},
+		{
+			name:   "same priority, p2's period is invalid",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 0, "1h", ""), now.Add(time.Second)),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 0, "foo", ""), now),
+			want:   true,
+		},
+		{
+			name:   "same priority and period, p1 added to the queue earlier",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 0, "100ms", ""), now),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 0, "100ms", ""), now.Add(time.Second)),
+			want:   true,
+		},
+		{
+			name:   "neither is periodic, p2 added to the queue earlier",
+			pInfo1: createQueuedPodInfo(makePeriodicPod("p1", 0, "", ""), now.Add(time.Second)),
+			pInfo2: createQueuedPodInfo(makePeriodicPod("p2", 0, "", ""), now),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := &RateMonotonic{tasks: newTaskCache()}
+			if got := rm.Less(tt.pInfo1, tt.pInfo2); got != tt.want {
+				t.Errorf("Less() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func makePeriodicPodWithCPU(name, period, execTime, cpu string) *v1.Pod {
+	pod := makePeriodicPod(name, 0, period, execTime)
+	pod.Spec.Containers = []v1.Container{{
+		Name: name,
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+		},
+	}}
+	return pod
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		nodeCPU      string
+		wantCode     framework.Code
+	}{
+		{
+			name:     "non-periodic pod is always admitted",
+			pod:      makePeriodicPod("p", 0, "", ""),
+			nodeCPU:  "1",
+			wantCode: framework.Success,
+		},
+		{
+			name: "pod without execution time is admitted",
+			pod:  makePeriodicPod("p", 0, "100ms", ""),
+			existingPods: []*v1.Pod{
+				makePeriodicPod("e1", 0, "100ms", "100ms"),
+			},
+			nodeCPU:  "1",
+			wantCode: framework.Success,
+		},
+		{
+			name: "utilization within the bound on a single core node",
+			pod:  makePeriodicPod("p", 0, "100ms", "30ms"),
+			existingPods: []*v1.Pod{
+				makePeriodicPod("e1", 0, "200ms", "60ms"),
+				makePeriodicPod("e2", 0, "", ""),
+			},
+			nodeCPU:  "1",
+			wantCode: framework.Success,
+		},
+		{
+			name: "utilization exceeds the bound on a single core node",
+			pod:  makePeriodicPod("p", 0, "100ms", "50ms"),
+			existingPods: []*v1.Pod{
+				makePeriodicPod("e1", 0, "200ms", "80ms"),
+			},
+			nodeCPU:  "1",
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name: "three single core tasks of 40% exceed the bound on a single core node",
+			pod:  makePeriodicPodWithCPU("p", "100ms", "40ms", "1"),
+			existingPods: []*v1.Pod{
+				makePeriodicPodWithCPU("e1", "100ms", "40ms", "1"),
+				makePeriodicPodWithCPU("e2", "100ms", "40ms", "1"),
+			},
+			nodeCPU:  "1",
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name: "three single core tasks of 40% fit on a 2 core node",
+			pod:  makePeriodicPodWithCPU("p", "100ms", "40ms", "1"),
+			existingPods: []*v1.Pod{
+				makePeriodicPodWithCPU("e1", "100ms", "40ms", "1"),
+				makePeriodicPodWithCPU("e2", "100ms", "40ms", "1"),
+			},
+			nodeCPU:  "2",
+			wantCode: framework.Success,
+		},
+		{
+			name: "three single core tasks of 40% fit on a 96 core node",
+			pod:  makePeriodicPodWithCPU("p", "100ms", "40ms", "1"),
+			existingPods: []*v1.Pod{
+				makePeriodicPodWithCPU("e1", "100ms", "40ms", "1"),
+				makePeriodicPodWithCPU("e2", "100ms", "40ms", "1"),
+			},
+			nodeCPU:  "96",
+			wantCode: framework.Success,
+		},
+		{
+			name: "three half core tasks of 40% fit on a single core node",
+			pod:  makePeriodicPodWithCPU("p", "100ms", "40ms", "500m"),
+			existingPods: []*v1.Pod{
+				makePeriodicPodWithCPU("e1", "100ms", "40ms", "500m"),
+				makePeriodicPodWithCPU("e2", "100ms", "40ms", "500m"),
+			},
+			nodeCPU:  "1",
+			wantCode: framework.Success,
+		},
+		{
+			name: "multi core tasks exceed the bound on a 4 core node",
+			pod:  makePeriodicPodWithCPU("p", "100ms", "50ms", "3"),
+			existingPods: []*v1.Pod{
+				makePeriodicPodWithCPU("e1", "100ms", "50ms", "4"),
+			},
+			nodeCPU:  "4",
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name: "existing pods with invalid annotations are ignored",
+			pod:  makePeriodicPod("p", 0, "100ms", "50ms"),
+			existingPods: []*v1.Pod{
+				makePeriodicPod("e1", 0, "200ms", "1s"),
+			},
+			nodeCPU:  "1",
+			wantCode: framework.Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo(tt.existingPods...)
+			nodeInfo.SetNode(st.MakeNode().Name("node").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: tt.nodeCPU}).Obj())
+			rm := &RateMonotonic{tasks: newTaskCache()}
+			state := framework.NewCycleState()
+			if _, status := rm.PreFilter(context.Background(), state, tt.pod); !status.IsSuccess() {
+				t.Fatalf("PreFilter() = %v, want success", status)
+			}
+			status := rm.Filter(context.Background(), state, tt.pod, nodeInfo)
+			if got := status.Code(); got != tt.wantCode {
+				t.Errorf("Filter() = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestPreFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		wantCode framework.Code
+		wantTask *Task
+	}{
+		{
+			name:     "non-periodic pod",
+			pod:      makePeriodicPod("p", 0, "", ""),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "periodic pod",
+			pod:      makePeriodicPod("p", 0, "100ms", "20ms"),
+			wantCode: framework.Success,
+			wantTask: &Task{Period: 100 * time.Millisecond, ExecTime: 20 * time.Millisecond},
+		},
+		{
+			name:     "unparsable period",
+			pod:      makePeriodicPod("p", 0, "foo", ""),
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:     "non-positive period",
+			pod:      makePeriodicPod("p", 0, "-1s", "50ms"),
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:     "execution time longer than the period",
+			pod:      makePeriodicPod("p", 0, "100ms", "1s"),
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := &RateMonotonic{tasks: newTaskCache()}
+			state := framework.NewCycleState()
+			_, status := rm.PreFilter(context.Background(), state, tt.pod)
+			if got := status.Code(); got != tt.wantCode {
+				t.Fatalf("PreFilter() = %v, want %v", got, tt.wantCode)
+			}
+			if !status.IsSuccess() {
+				return
+			}
+			s, err := getPreFilterState(state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(s.task, tt.wantTask) {
+				t.Errorf("preFilterState.task = %v, want %v", s.task, tt.wantTask)
+			}
+		})
+	}
+}
+
+func TestTaskCache(t *testing.T) {
+	c := newTaskCache()
+	pod := makePeriodicPod("p", 0, "100ms", "")
+	pod.UID = "uid"
+
+	if got := c.get(pod); got == nil || got.Period != 100*time.Millisecond {
+		t.Fatalf("get() = %v, want period 100ms", got)
+	}
+	pod.Annotations[AnnotationKeyPeriod] = "1s"
+	if got := c.get(pod); got == nil || got.Period != time.Second {
+		t.Errorf("get() after annotation update = %v, want period 1s", got)
+	}
+	pod.Annotations[AnnotationKeyPeriod] = "foo"
+	if got := c.get(pod); got != nil {
+		t.Errorf("get() with invalid annotation = %v, want nil", got)
+	}
+
+	c.deletePod(cache.DeletedFinalStateUnknown{Key: "default/p", Obj: pod})
+	if _, ok := c.items[pod.UID]; ok {
+		t.Errorf("expected cache entry of pod %v to be removed", pod.UID)
+	}
+}
+
+func TestCompareRank(t *testing.T) {
+	tests := []struct {
+		name string
+		p1   *v1.Pod
+		p2   *v1.Pod
+		want int
+	}{
+		{
+			name: "higher priority wins over a shorter period",
+			p1:   makePeriodicPod("p1", 10, "1s", ""),
+			p2:   makePeriodicPod("p2", 0, "10ms", ""),
+			want: -1,
+		},
+		{
+			name: "shorter period wins at the same priority",
+			p1:   makePeriodicPod("p1", 0, "1s", ""),
+			p2:   makePeriodicPod("p2", 0, "10ms", ""),
+			want: 1,
+		},
+		{
+			name: "periodic pod wins over non-periodic pod",
+			p1:   makePeriodicPod("p1", 0, "1h", ""),
+			p2:   makePeriodicPod("p2", 0, "", ""),
+			want: -1,
+		},
+		{
+			name: "equal periods",
+			p1:   makePeriodicPod("p1", 0, "100ms", "10ms"),
+			p2:   makePeriodicPod("p2", 0, "100ms", "20ms"),
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := &RateMonotonic{tasks: newTaskCache()}
+			if got := rm.compareRank(tt.p1, tt.p2); got != tt.want {
+				t.Errorf("compareRank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUtilizationBound(t *testing.T) {
+	tests := []struct {
+		n    int
+		want float64
+	}{
+		{n: 1, want: 1},
+		{n: 2, want: 0.8284},
+		{n: 3, want: 0.7798},
+		{n: 1000, want: 0.6934},
+	}
+	for _, tt := range tests {
+		if got := utilizationBound(tt.n); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("utilizationBound(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCanPreempt(t *testing.T) {
+	tests := []struct {
+		name      string
+		preemptor *v1.Pod
+		victim    *v1.Pod
+		want      bool
+	}{
+		{
+			name:      "lower priority victim",
+			preemptor: makePeriodicPod("p", 10, "", ""),
+			victim:    makePeriodicPod("v", 0, "10ms", ""),
+			want:      true,
+		},
+		{
+			name:      "higher priority victim with a longer period",
+			preemptor: makePeriodicPod("p", 0, "10ms", ""),
+			victim:    makePeriodicPod("v", 10, "1s", ""),
+			want:      false,
+		},
+		{
+			name:      "same priority, victim has a longer period",
+			preemptor: makePeriodicPod("p", 0, "10ms", ""),
+			victim:    makePeriodicPod("v", 0, "1s", ""),
+			want:      true,
+		},
+		{
+			name:      "same priority, victim has the same period",
+			preemptor: makePeriodicPod("p", 0, "10ms", ""),
+			victim:    makePeriodicPod("v", 0, "10ms", ""),
+			want:      false,
+		},
+		{
+			name:      "same priority, victim is not periodic",
+			preemptor: makePeriodicPod("p", 0, "10ms", ""),
+			victim:    makePeriodicPod("v", 0, "", ""),
+			want:      false,
+		},
+		{
+			name:      "same priority, preemptor is not periodic",
+			preemptor: makePeriodicPod("p", 0, "", ""),
+			victim:    makePeriodicPod("v", 0, "1s", ""),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := &RateMonotonic{tasks: newTaskCache()}
+			if got := rm.canPreempt(tt.preemptor, tt.victim); got != tt.want {
+				t.Errorf("canPreempt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func makeVictimPod(name string, priority int32, period, cpu string) *v1.Pod {
+	pw := st.MakePod().Name(name).Namespace("default").UID(name).Priority(priority).Node("node1").
+		Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu})
+	if period != "" {
+		pw = pw.Annotation(AnnotationKeyPeriod, period)
+	}
+	return pw.Obj()
+}
+
+func makePDB(name string, matchLabels map[string]string, disruptionsAllowed int32) *policy.PodDisruptionBudget {
+	return &policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       policy.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: matchLabels}},
+		Status:     policy.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+func TestSelectVictimsOnNode(t *testing.T) {
+	withLabel := func(pod *v1.Pod) *v1.Pod {
+		pod.Labels = map[string]string{"app": "protected"}
+		return pod
+	}
+	tests := []struct {
+		name                   string
+		preemptor              *v1.Pod
+		pods                   []*v1.Pod
+		pdbs                   []*policy.PodDisruptionBudget
+		wantVictims            []string
+		wantNumViolatingVictim int
+		wantCode               framework.Code
+	}{
+		{
+			name:        "same priority pod with a longer period is a victim",
+			preemptor:   makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods:        []*v1.Pod{makeVictimPod("p1", 0, "1s", "4")},
+			wantVictims: []string{"p1"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:      "same priority pod with a shorter period is not a victim",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods:      []*v1.Pod{makeVictimPod("p1", 0, "10ms", "4")},
+			wantCode:  framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:      "same priority pod with an equal period is not a victim",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods:      []*v1.Pod{makeVictimPod("p1", 0, "100ms", "4")},
+			wantCode:  framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:      "same priority non-periodic pod is not a victim",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods:      []*v1.Pod{makeVictimPod("p1", 0, "", "4")},
+			wantCode:  framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:      "higher priority pod is never a victim",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods:      []*v1.Pod{makeVictimPod("p1", 100, "1s", "4")},
+			wantCode:  framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:        "lower priority pod is a victim regardless of its period",
+			preemptor:   makeVictimPod("preemptor", 100, "1s", "2"),
+			pods:        []*v1.Pod{makeVictimPod("p1", 0, "10ms", "4")},
+			wantVictims: []string{"p1"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:      "only the pods with longer periods are victims",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods: []*v1.Pod{
+				makeVictimPod("p1", 0, "10ms", "2"),
+				makeVictimPod("p2", 0, "1s", "2"),
+			},
+			wantVictims: []string{"p2"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:      "victims that don't need to be evicted are reprieved, longest period last",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods: []*v1.Pod{
+				makeVictimPod("p1", 0, "1s", "2"),
+				makeVictimPod("p2", 0, "10s", "2"),
+			},
+			wantVictims: []string{"p2"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:      "pod violating a PDB is reprieved first",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "2"),
+			pods: []*v1.Pod{
+				withLabel(makeVictimPod("p1", 0, "10s", "2")),
+				makeVictimPod("p2", 0, "1s", "2"),
+			},
+			pdbs:        []*policy.PodDisruptionBudget{makePDB("pdb", map[string]string{"app": "protected"}, 0)},
+			wantVictims: []string{"p2"},
+			wantCode:    framework.Success,
+		},
+		{
+			name:      "PDB violating victims are counted",
+			preemptor: makeVictimPod("preemptor", 0, "100ms", "4"),
+			pods: []*v1.Pod{
+				withLabel(makeVictimPod("p1", 0, "1s", "2")),
+				withLabel(makeVictimPod("p2", 0, "10s", "2")),
+			},
+			pdbs:                   []*policy.PodDisruptionBudget{makePDB("pdb", map[string]string{"app": "protected"}, 1)},
+			wantVictims:            []string{"p1", "p2"},
+			wantNumViolatingVictim: 1,
+			wantCode:               framework.Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			node := st.MakeNode().Name("node1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj()
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			registeredPlugins := []st.RegisterPluginFunc{
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				st.RegisterPluginAsExtensions(noderesources.Name, fwkruntime.FactoryAdapter(feature.Features{}, noderesources.NewFit), "Filter", "PreFilter"),
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}
+			fwk, err := st.NewFramework(ctx, registeredPlugins, "default-scheduler",
+				fwkruntime.WithInformerFactory(informerFactory),
+				fwkruntime.WithPodNominator(tu.NewPodNominator(nil)),
+				fwkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(tt.pods, []*v1.Node{node})),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pl, err := New(&config.RateMonotonicArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100}, fwk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rm := pl.(*RateMonotonic)
+
+			state := framework.NewCycleState()
+			if _, status := fwk.RunPreFilterPlugins(ctx, state, tt.preemptor); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			nodeInfo := framework.NewNodeInfo(tt.pods...)
+			nodeInfo.SetNode(node)
+
+			victims, numViolatingVictim, status := rm.SelectVictimsOnNode(ctx, state, tt.preemptor, nodeInfo, tt.pdbs)
+			if status.Code() != tt.wantCode {
+				t.Fatalf("Unexpected status code: want %v, got %v (%v)", tt.wantCode, status.Code(), status.Message())
+			}
+			var gotVictims []string
+			for _, p := range victims {
+				gotVictims = append(gotVictims, p.Name)
+			}
+			sort.Strings(gotVictims)
+			if !reflect.DeepEqual(gotVictims, tt.wantVictims) {
+				t.Errorf("Unexpected victims: want %v, got %v", tt.wantVictims, gotVictims)
+			}
+			if numViolatingVictim != tt.wantNumViolatingVictim {
+				t.Errorf("Unexpected numViolatingVictim: want %d, got %d", tt.wantNumViolatingVictim, numViolatingVictim)
+			}
+		})
+	}
+}
+
+func TestCalculateNumCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       config.RateMonotonicArgs
+		numNodes   int32
+		wantResult int32
+	}{
+		{
+			name:       "minimum absolute wins over percentage",
+			args:       config.RateMonotonicArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
+			numNodes:   500,
+			wantResult: 100,
+		},
+		{
+			name:       "percentage wins over minimum absolute",
+			args:       config.RateMonotonicArgs{MinCandidateNodesPercentage: 30, MinCandidateNodesAbsolute: 10},
+			numNodes:   500,
+			wantResult: 150,
+		},
+		{
+			name:       "capped by the number of nodes",
+			args:       config.RateMonotonicArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 100},
+			numNodes:   50,
+			wantResult: 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := &RateMonotonic{args: tt.args}
+			if got := rm.calculateNumCandidates(tt.numNodes); got != tt.wantResult {
+				t.Errorf("calculateNumCandidates() = %d, want %d", got, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestNewWithInvalidArgs(t *testing.T) {
+	args := &config.RateMonotonicArgs{MinCandidateNodesPercentage: 0, MinCandidateNodesAbsolute: 0}
+	if _, err := New(args, nil); err == nil {
+		t.Error("expected an error for invalid args")
+	}
+}
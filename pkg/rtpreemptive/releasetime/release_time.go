@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releasetime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "ReleaseTime"
+
+	// AnnotationKeyReleaseTime is the annotation key of the time, in RFC 3339 format,
+	// before which a pod is kept out of the active queue.
+	AnnotationKeyReleaseTime = "rt-preemptive.scheduling.x-k8s.io/release-time"
+	// AnnotationKeyReleased is the annotation the plugin sets on a pod when its release
+	// time arrives. The resulting pod update makes the scheduling queue run PreEnqueue
+	// for the pod again.
+	AnnotationKeyReleased = "rt-preemptive.scheduling.x-k8s.io/released"
+
+	// ErrReasonNotReleased is the reason for a pod whose release time hasn't arrived yet.
+	ErrReasonNotReleased = "pod hasn't reached its release time"
+
+	// EventReasonInvalidAnnotations is the reason of the warning Event recorded on a pod
+	// with malformed rt-preemptive annotations.
+	EventReasonInvalidAnnotations = "InvalidAnnotations"
+
+	// releaseTimeout bounds each attempt to annotate a pod as released.
+	releaseTimeout = 10 * time.Second
+)
+
+// ReleaseTime is a PreEnqueue plugin that holds back pods until their release time.
+type ReleaseTime struct {
+	handle framework.Handle
+	clock  clock.WithDelayedExecution
+	args   config.ReleaseTimeArgs
+
+	sync.Mutex
+	// timers holds the pending release of each held back pod, keyed by pod UID.
+	timers map[types.UID]*releaseTimer
+}
+
+type releaseTimer struct {
+	releaseTime time.Time
+	timer       clock.Timer
+}
+
+var _ framework.PreEnqueuePlugin = &ReleaseTime{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (rt *ReleaseTime) Name() string {
+	return Name
+}
+
+// Option configures a ReleaseTime plugin built by NewFactory.
+type Option func(*ReleaseTime)
+
+// WithClock sets the clock used to wait for release times. It defaults to the real clock.
+func WithClock(clk clock.WithDelayedExecution) Option {
+	return func(rt *ReleaseTime) {
+		rt.clock = clk
+	}
+}
+
+// NewFactory returns a plugin factory that builds the plugin with the given options
+// applied, so that tests and downstream schedulers can inject their own dependencies.
+func NewFactory(opts ...Option) frameworkruntime.PluginFactory {
+	return func(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		args, ok := obj.(*config.ReleaseTimeArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type ReleaseTimeArgs, got %T", obj)
+		}
+		rt := &ReleaseTime{
+			handle: handle,
+			clock:  clock.RealClock{},
+			args:   *args,
+			timers: make(map[types.UID]*releaseTimer),
+		}
+		for _, opt := range opts {
+			opt(rt)
+		}
+		handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			DeleteFunc: rt.deletePod,
+		})
+		return rt, nil
+	}
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	return NewFactory()(obj, handle)
+}
+
+// PreEnqueue rejects pods whose release time is still in the future and arranges for
+// them to be requeued once it arrives. A malformed release time is reported with a
+// warning Event; the pod is rejected until the annotation is fixed in strict mode, and
+// enqueued as if it had no release time otherwise. A pending release is cancelled
+// whenever the pod no longer needs one.
+func (rt *ReleaseTime) PreEnqueue(ctx context.Context, pod *v1.Pod) *framework.Status {
+	releaseTime, err := parseReleaseTime(pod)
+	if err != nil {
+		rt.cancelRelease(pod.UID)
+		rt.handle.EventRecorder().Eventf(pod, nil, v1.EventTypeWarning, EventReasonInvalidAnnotations, "PreEnqueue", "Malformed rt-preemptive annotations: %v", err)
+		if rt.args.StrictValidation {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+		}
+		return nil
+	}
+	if releaseTime.IsZero() {
+		rt.cancelRelease(pod.UID)
+		return nil
+	}
+	wait := releaseTime.Sub(rt.clock.Now())
+	if wait <= 0 {
+		rt.cancelRelease(pod.UID)
+		return nil
+	}
+	rt.scheduleRelease(pod, releaseTime, wait)
+	return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("%s: %s", ErrReasonNotReleased, releaseTime.Format(time.RFC3339)))
+}
+
+// scheduleRelease starts a timer that releases the pod after the given duration. A pod
+// has at most one pending release; it is replaced when the release time changes.
+func (rt *ReleaseTime) scheduleRelease(pod *v1.Pod, releaseTime time.Time, wait time.Duration) {
+	rt.Lock()
+	defer rt.Unlock()
+	if rtimer, ok := rt.timers[pod.UID]; ok {
+		if rtimer.releaseTime.Equal(releaseTime) {
+			return
+		}
+		rtimer.timer.Stop()
+	}
+	namespace, name, uid := pod.Namespace, pod.Name, pod.UID
+	rt.timers[uid] = &releaseTimer{
+		releaseTime: releaseTime,
+		timer: rt.clock.AfterFunc(wait, func() {
+			rt.release(namespace, name, uid, releaseTime)
+		}),
+	}
+}
+
+// release annotates the pod with its release time, which triggers a pod update event
+// that moves the pod from the unschedulable pods back through PreEnqueue. Failed patches
+// are retried with a short backoff, so that the pod isn't left waiting for the periodic
+// flush of unschedulable pods.
+func (rt *ReleaseTime) release(namespace, name string, uid types.UID, releaseTime time.Time) {
+	rt.Lock()
+	rtimer, ok := rt.timers[uid]
+	if !ok || !rtimer.releaseTime.Equal(releaseTime) {
+		// The release was cancelled or replaced after the timer fired.
+		rt.Unlock()
+		return
+	}
+	delete(rt.timers, uid)
+	rt.Unlock()
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationKeyReleased, releaseTime.Format(time.RFC3339Nano))
+	err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return !apierrors.IsNotFound(err)
+	}, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer cancel()
+		_, err := rt.handle.ClientSet().CoreV1().Pods(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+		return err
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to release pod", "pod", klog.KRef(namespace, name))
+	}
+}
+
+// deletePod stops the pending release of a deleted pod.
+func (rt *ReleaseTime) deletePod(obj interface{}) {
+	var pod *v1.Pod
+	switch t := obj.(type) {
+	case *v1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		if pod, ok = t.Obj.(*v1.Pod); !ok {
+			return
+		}
+	default:
+		return
+	}
+	rt.cancelRelease(pod.UID)
+}
+
+// cancelRelease stops the pending release of the pod with the given UID, if any.
+func (rt *ReleaseTime) cancelRelease(uid types.UID) {
+	rt.Lock()
+	defer rt.Unlock()
+	if rtimer, ok := rt.timers[uid]; ok {
+		rtimer.timer.Stop()
+		delete(rt.timers, uid)
+	}
+}
+
+// parseReleaseTime returns the release time of the given pod, or the zero time if the
+// pod isn't annotated with one.
+func parseReleaseTime(pod *v1.Pod) (time.Time, error) {
+	value, ok := pod.Annotations[AnnotationKeyReleaseTime]
+	if !ok {
+		return time.Time{}, nil
+	}
+	releaseTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("annotation %s must be an RFC 3339 time, got %q", AnnotationKeyReleaseTime, value)
+	}
+	return releaseTime, nil
+}
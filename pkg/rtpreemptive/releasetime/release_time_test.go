@@ -0,0 +1,320 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releasetime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+var now = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func makePod(name, releaseTime string) *v1.Pod {
+	pw := st.MakePod().Name(name).Namespace("default").UID(name)
+	if releaseTime != "" {
+		pw = pw.Annotation(AnnotationKeyReleaseTime, releaseTime)
+	}
+	return pw.Obj()
+}
+
+func newTestReleaseTime(t *testing.T, ctx context.Context, clk *testingclock.FakeClock, objs ...runtime.Object) (*ReleaseTime, *clientsetfake.Clientset) {
+	cs := clientsetfake.NewSimpleClientset(objs...)
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+	}
+	fh, err := st.NewFramework(ctx, registeredPlugins, "default-scheduler",
+		fwkruntime.WithClientSet(cs),
+		fwkruntime.WithInformerFactory(informerFactory),
+		fwkruntime.WithEventRecorder(events.NewFakeRecorder(10)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewFactory(WithClock(clk))(&config.ReleaseTimeArgs{}, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*ReleaseTime), cs
+}
+
+func TestPreEnqueue(t *testing.T) {
+	tests := []struct {
+		name      string
+		pod       *v1.Pod
+		strict    bool
+		wantCode  framework.Code
+		wantTimer bool
+		wantEvent bool
+	}{
+		{
+			name:     "pod without a release time",
+			pod:      makePod("p", ""),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "pod whose release time has passed",
+			pod:      makePod("p", now.Add(-time.Minute).Format(time.RFC3339)),
+			wantCode: framework.Success,
+		},
+		{
+			name:     "pod whose release time is now",
+			pod:      makePod("p", now.Format(time.RFC3339)),
+			wantCode: framework.Success,
+		},
+		{
+			name:      "pod whose release time is in the future",
+			pod:       makePod("p", now.Add(time.Minute).Format(time.RFC3339)),
+			wantCode:  framework.UnschedulableAndUnresolvable,
+			wantTimer: true,
+		},
+		{
+			name:      "pod with a malformed release time",
+			pod:       makePod("p", "1m"),
+			wantCode:  framework.Success,
+			wantEvent: true,
+		},
+		{
+			name:      "pod with a malformed release time in strict mode",
+			pod:       makePod("p", "1m"),
+			strict:    true,
+			wantCode:  framework.UnschedulableAndUnresolvable,
+			wantEvent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			clk := testingclock.NewFakeClock(now)
+			rt, _ := newTestReleaseTime(t, ctx, clk)
+			rt.args.StrictValidation = tt.strict
+			if got := rt.PreEnqueue(ctx, tt.pod); got.Code() != tt.wantCode {
+				t.Errorf("Expected status code %v, got %v (%v)", tt.wantCode, got.Code(), got.Message())
+			}
+			if got := clk.HasWaiters(); got != tt.wantTimer {
+				t.Errorf("Expected a pending release: %v, got %v", tt.wantTimer, got)
+			}
+
+			recorder := rt.handle.EventRecorder().(*events.FakeRecorder)
+			select {
+			case event := <-recorder.Events:
+				if !tt.wantEvent {
+					t.Errorf("Unexpected event %q", event)
+				} else if want := v1.EventTypeWarning + " " + EventReasonInvalidAnnotations; !strings.HasPrefix(event, want) || !strings.Contains(event, AnnotationKeyReleaseTime) {
+					t.Errorf("Expected a %q event naming %s, got %q", want, AnnotationKeyReleaseTime, event)
+				}
+			default:
+				if tt.wantEvent {
+					t.Errorf("Expected a warning event")
+				}
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pod := makePod("p", now.Add(time.Minute).Format(time.RFC3339))
+	clk := testingclock.NewFakeClock(now)
+	rt, cs := newTestReleaseTime(t, ctx, clk, pod)
+
+	// Rejecting the same pod again doesn't start another timer.
+	for i := 0; i < 2; i++ {
+		if got := rt.PreEnqueue(ctx, pod); got.Code() != framework.UnschedulableAndUnresolvable {
+			t.Fatalf("Expected the pod to be held back, got %v", got.Code())
+		}
+	}
+	if got := len(rt.timers); got != 1 {
+		t.Fatalf("Expected 1 pending release, got %d", got)
+	}
+
+	clk.Step(30 * time.Second)
+	got, err := cs.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Annotations[AnnotationKeyReleased]; ok {
+		t.Errorf("Expected the pod not to be released before its release time")
+	}
+
+	clk.Step(30 * time.Second)
+	got, err = cs.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(time.Minute).Format(time.RFC3339Nano); got.Annotations[AnnotationKeyReleased] != want {
+		t.Errorf("Expected annotation %s=%q, got %q", AnnotationKeyReleased, want, got.Annotations[AnnotationKeyReleased])
+	}
+	if len(rt.timers) != 0 {
+		t.Errorf("Expected no pending release, got %d", len(rt.timers))
+	}
+	if status := rt.PreEnqueue(ctx, got); !status.IsSuccess() {
+		t.Errorf("Expected the released pod to be enqueued, got %v", status.Code())
+	}
+}
+
+func TestReleaseRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pod := makePod("p", now.Add(time.Minute).Format(time.RFC3339))
+	clk := testingclock.NewFakeClock(now)
+	rt, cs := newTestReleaseTime(t, ctx, clk, pod)
+
+	// The first patch fails, the retry succeeds.
+	failed := false
+	cs.PrependReactor("patch", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if failed {
+			return false, nil, nil
+		}
+		failed = true
+		return true, nil, apierrors.NewServiceUnavailable("unavailable")
+	})
+	rt.PreEnqueue(ctx, pod)
+	clk.Step(time.Minute)
+
+	got, err := cs.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !failed {
+		t.Errorf("Expected the first release attempt to fail")
+	}
+	if _, ok := got.Annotations[AnnotationKeyReleased]; !ok {
+		t.Errorf("Expected the pod to be released after a failed attempt")
+	}
+}
+
+func TestReleaseTimeChanged(t *testing.T) {
+	tests := []struct {
+		name         string
+		releaseTime  string
+		wantReleased bool
+	}{
+		{
+			name:         "release time moved earlier",
+			releaseTime:  now.Add(time.Minute).Format(time.RFC3339),
+			wantReleased: true,
+		},
+		{
+			name:        "release time moved into the past",
+			releaseTime: now.Add(-time.Minute).Format(time.RFC3339),
+		},
+		{
+			name: "release time removed",
+		},
+		{
+			name:        "release time made malformed",
+			releaseTime: "1m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pod := makePod("p", now.Add(time.Hour).Format(time.RFC3339))
+			clk := testingclock.NewFakeClock(now)
+			rt, cs := newTestReleaseTime(t, ctx, clk, pod)
+			rt.PreEnqueue(ctx, pod)
+
+			// The pending release of the old release time is stopped.
+			rt.PreEnqueue(ctx, makePod("p", tt.releaseTime))
+			if tt.wantReleased {
+				if got := len(rt.timers); got != 1 {
+					t.Fatalf("Expected 1 pending release, got %d", got)
+				}
+				clk.Step(time.Minute)
+			} else if got := len(rt.timers); got != 0 {
+				t.Fatalf("Expected no pending release, got %d", got)
+			}
+			if clk.HasWaiters() {
+				t.Errorf("Expected the previous release to be stopped")
+			}
+
+			clk.Step(time.Hour)
+			got, err := cs.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := got.Annotations[AnnotationKeyReleased]; ok != tt.wantReleased {
+				t.Errorf("Expected the pod to be released: %v, got %v", tt.wantReleased, ok)
+			}
+		})
+	}
+}
+
+func TestReleaseDeletedPod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The pod doesn't exist in the API server, so releasing it is a no-op.
+	pod := makePod("p", now.Add(time.Minute).Format(time.RFC3339))
+	clk := testingclock.NewFakeClock(now)
+	rt, _ := newTestReleaseTime(t, ctx, clk)
+	rt.PreEnqueue(ctx, pod)
+	clk.Step(time.Minute)
+	if len(rt.timers) != 0 {
+		t.Errorf("Expected no pending release, got %d", len(rt.timers))
+	}
+}
+
+func TestDeletePod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p1 := makePod("p1", now.Add(time.Minute).Format(time.RFC3339))
+	p2 := makePod("p2", now.Add(time.Minute).Format(time.RFC3339))
+	clk := testingclock.NewFakeClock(now)
+	rt, _ := newTestReleaseTime(t, ctx, clk)
+	rt.PreEnqueue(ctx, p1)
+	rt.PreEnqueue(ctx, p2)
+
+	rt.deletePod(p1)
+	rt.deletePod(cache.DeletedFinalStateUnknown{Key: "default/p2", Obj: p2})
+	if len(rt.timers) != 0 {
+		t.Errorf("Expected no pending release, got %d", len(rt.timers))
+	}
+	if clk.HasWaiters() {
+		t.Errorf("Expected the pending releases to be stopped")
+	}
+}
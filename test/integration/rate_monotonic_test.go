@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/scheduler"
+	schedapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	imageutils "k8s.io/kubernetes/test/utils/image"
+
+	schedconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/rtpreemptive/ratemonotonic"
+	"sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestRateMonotonicPlugin(t *testing.T) {
+	testCtx := &testContext{}
+
+	cs := kubernetes.NewForConfigOrDie(globalKubeConfig)
+	testCtx.ClientSet = cs
+	testCtx.KubeConfig = globalKubeConfig
+
+	testPriority := int32(1000)
+	pauseImage := imageutils.GetPauseImageName()
+	podRequest := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	// nodeCapacity < 2 * podRequest
+	nodeCapacity := map[v1.ResourceName]string{
+		v1.ResourceCPU:    "3",
+		v1.ResourceMemory: "3Gi",
+	}
+	node := st.MakeNode().Name("node-a").Capacity(nodeCapacity).Obj()
+
+	makePeriodicPod := func(name string, priority int32, period string) *v1.Pod {
+		pw := st.MakePod().Name(name).Container(pauseImage).ZeroTerminationGracePeriod().Priority(priority)
+		if period != "" {
+			pw = pw.Annotation(ratemonotonic.AnnotationKeyPeriod, period)
+		}
+		return makePod(pw).ResourceRequests(podRequest).Obj()
+	}
+
+	tests := []struct {
+		name            string
+		victimCandidate *v1.Pod
+		preemptor       *v1.Pod
+		wantPreempted   bool
+	}{
+		{
+			name:            "same priority, the victim candidate with a longer period is preempted",
+			victimCandidate: makePeriodicPod("victim-candidate", testPriority, "1s"),
+			preemptor:       makePeriodicPod("p", testPriority, "100ms"),
+			wantPreempted:   true,
+		},
+		{
+			name:            "same priority, the victim candidate with a shorter period is not preempted",
+			victimCandidate: makePeriodicPod("victim-candidate", testPriority, "10ms"),
+			preemptor:       makePeriodicPod("p", testPriority, "100ms"),
+			wantPreempted:   false,
+		},
+		{
+			name:            "same priority, the non-periodic victim candidate is not preempted",
+			victimCandidate: makePeriodicPod("victim-candidate", testPriority, ""),
+			preemptor:       makePeriodicPod("p", testPriority, "100ms"),
+			wantPreempted:   false,
+		},
+		{
+			name:            "the victim candidate with a higher priority is not preempted even with a longer period",
+			victimCandidate: makePeriodicPod("victim-candidate", testPriority+10, "1s"),
+			preemptor:       makePeriodicPod("p", testPriority, "100ms"),
+			wantPreempted:   false,
+		},
+		{
+			name:            "the victim candidate with a lower priority is preempted even with a shorter period",
+			victimCandidate: makePeriodicPod("victim-candidate", testPriority, "10ms"),
+			preemptor:       makePeriodicPod("p", testPriority+10, "1s"),
+			wantPreempted:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCtx.Ctx, testCtx.CancelFn = context.WithCancel(context.Background())
+
+			// prepare test cluster
+			registry := fwkruntime.Registry{ratemonotonic.Name: ratemonotonic.New}
+			cfg, err := util.NewDefaultSchedulerComponentConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Profiles[0].Plugins.QueueSort = schedapi.PluginSet{
+				Enabled:  []schedapi.Plugin{{Name: ratemonotonic.Name}},
+				Disabled: []schedapi.Plugin{{Name: "*"}},
+			}
+			cfg.Profiles[0].Plugins.PreFilter.Enabled = append(cfg.Profiles[0].Plugins.PreFilter.Enabled, schedapi.Plugin{Name: ratemonotonic.Name})
+			cfg.Profiles[0].Plugins.Filter.Enabled = append(cfg.Profiles[0].Plugins.Filter.Enabled, schedapi.Plugin{Name: ratemonotonic.Name})
+			cfg.Profiles[0].Plugins.PostFilter = schedapi.PluginSet{
+				Enabled:  []schedapi.Plugin{{Name: ratemonotonic.Name}},
+				Disabled: []schedapi.Plugin{{Name: "*"}},
+			}
+			cfg.Profiles[0].PluginConfig = append(cfg.Profiles[0].PluginConfig, schedapi.PluginConfig{
+				Name: ratemonotonic.Name,
+				Args: &schedconfig.RateMonotonicArgs{
+					MinCandidateNodesPercentage: 10,
+					MinCandidateNodesAbsolute:   100,
+				},
+			})
+
+			ns := fmt.Sprintf("integration-test-%v", string(uuid.NewUUID()))
+			createNamespace(t, testCtx, ns)
+
+			testCtx = initTestSchedulerWithOptions(
+				t,
+				testCtx,
+				scheduler.WithProfiles(cfg.Profiles[0]),
+				scheduler.WithFrameworkOutOfTreeRegistry(registry),
+				scheduler.WithPodInitialBackoffSeconds(int64(0)),
+				scheduler.WithPodMaxBackoffSeconds(int64(0)),
+			)
+			syncInformerFactory(testCtx)
+			go testCtx.Scheduler.Run(testCtx.Ctx)
+			defer cleanupTest(t, testCtx)
+
+			// Create test node
+			if _, err := cs.CoreV1().Nodes().Create(testCtx.Ctx, node, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create node: %v", err)
+			}
+
+			// Create victim candidate pod and it should be scheduled
+			victimCandidate, err := cs.CoreV1().Pods(ns).Create(testCtx.Ctx, tt.victimCandidate, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("failed to create victim candidate pod %q: %v", tt.victimCandidate.Name, err)
+			}
+			if err := wait.Poll(1*time.Second, 60*time.Second, func() (bool, error) {
+				return podScheduled(cs, ns, victimCandidate.Name), nil
+			}); err != nil {
+				t.Fatalf("victim candidate pod %q failed to be scheduled: %v", victimCandidate.Name, err)
+			}
+
+			// Create the preemptor pod.
+			preemptor, err := cs.CoreV1().Pods(ns).Create(testCtx.Ctx, tt.preemptor, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("failed to create preemptor Pod %q: %v", tt.preemptor.Name, err)
+			}
+
+			defer cleanupPods(t, testCtx, []*v1.Pod{
+				victimCandidate,
+				preemptor,
+			})
+
+			if tt.wantPreempted {
+				// - the preemptor pod got scheduled successfully
+				// - the victim pod does not exist (preempted)
+				if err := wait.Poll(1*time.Second, 30*time.Second, func() (bool, error) {
+					return podScheduled(cs, ns, preemptor.Name) && util.PodNotExist(cs, ns, victimCandidate.Name), nil
+				}); err != nil {
+					t.Fatalf("preemptor pod %q failed to be scheduled: %v", preemptor.Name, err)
+				}
+			} else {
+				// - the victim candidate pod keeps being scheduled, and
+				// - the preemptor pod is not scheduled
+				if err := consistently(1*time.Second, 15*time.Second, func() (bool, error) {
+					a := podScheduled(cs, ns, victimCandidate.Name)
+					b := podScheduled(cs, ns, preemptor.Name)
+					t.Logf("%s scheduled=%v, %s scheduled = %v", victimCandidate.Name, a, preemptor.Name, b)
+					return a && !b, nil
+				}); err != nil {
+					t.Fatalf("preemptor pod %q was scheduled: %v", preemptor.Name, err)
+				}
+			}
+		})
+	}
+}